@@ -0,0 +1,176 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Config holds the tunable parameters for logging and rotation. Every
+// field is overridable via environment variables so operators can tune
+// retention, size thresholds, and timing without recompiling.
+type Config struct {
+	LogFile       string
+	MaxSize       int64         // bytes; rotate when the active log reaches this size
+	MaxBackups    int           // number of rotated backups to keep (0 = unlimited)
+	MaxAge        time.Duration // delete backups older than this (0 = unlimited)
+	Compress      bool          // gzip rotated backups asynchronously
+	RotateDaily   bool          // also rotate once every 24h regardless of size
+	WriteInterval time.Duration
+	LogFormat     string // "text" or "json"
+	LogLevel      string // DEBUG, INFO, WARN, or ERROR
+	FlushInterval time.Duration
+
+	StderrLevel string // minimum level mirrored to stderr
+
+	SyslogEnabled  bool
+	SyslogFacility string
+	SyslogLevel    string
+
+	HTTPSinkURL           string
+	HTTPSinkLevel         string
+	HTTPSinkBatchSize     int
+	HTTPSinkFlushInterval time.Duration
+	HTTPSinkMaxRetries    int
+}
+
+func loadConfig() Config {
+	return Config{
+		LogFile:       getEnv("APP_LOG_FILE", "/var/log/app.log"),
+		MaxSize:       getEnvInt64("APP_LOG_MAX_SIZE", 1024*1024),
+		MaxBackups:    getEnvInt("APP_LOG_MAX_BACKUPS", 5),
+		MaxAge:        getEnvDuration("APP_LOG_MAX_AGE", 7*24*time.Hour),
+		Compress:      getEnvBool("APP_LOG_COMPRESS", false),
+		RotateDaily:   getEnvBool("APP_LOG_ROTATE_DAILY", true),
+		WriteInterval: getEnvPositiveDuration("APP_WRITE_INTERVAL", 30*time.Second),
+		LogFormat:     getEnv("APP_LOG_FORMAT", "text"),
+		LogLevel:      getEnv("APP_LOG_LEVEL", "INFO"),
+		FlushInterval: getEnvPositiveDuration("APP_LOG_FLUSH_INTERVAL", 1*time.Second),
+
+		StderrLevel: getEnv("APP_STDERR_LEVEL", "INFO"),
+
+		SyslogEnabled:  getEnvBool("APP_SYSLOG_ENABLED", false),
+		SyslogFacility: getEnv("APP_SYSLOG_FACILITY", "USER"),
+		SyslogLevel:    getEnv("APP_SYSLOG_LEVEL", "WARN"),
+
+		HTTPSinkURL:           getEnv("APP_HTTP_SINK_URL", ""),
+		HTTPSinkLevel:         getEnv("APP_HTTP_SINK_LEVEL", "WARN"),
+		HTTPSinkBatchSize:     getEnvInt("APP_HTTP_SINK_BATCH_SIZE", 20),
+		HTTPSinkFlushInterval: getEnvPositiveDuration("APP_HTTP_SINK_FLUSH_INTERVAL", 5*time.Second),
+		HTTPSinkMaxRetries:    getEnvInt("APP_HTTP_SINK_MAX_RETRIES", 3),
+	}
+}
+
+// reloadConfig re-reads APP_CONFIG_FILE (if set) over the current
+// environment and rebuilds the Config from it, so SIGUSR2 can pick up
+// changes to rotation size, write interval, and log level without a
+// restart.
+func reloadConfig() (Config, error) {
+	if path := os.Getenv("APP_CONFIG_FILE"); path != "" {
+		if err := applyConfigFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+	return loadConfig(), nil
+}
+
+// applyConfigFile overlays simple KEY=VALUE lines from path onto the
+// process environment; blank lines and lines starting with # are
+// ignored. Values use the same names as the APP_* environment
+// variables loadConfig reads.
+func applyConfigFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		os.Setenv(strings.TrimSpace(key), strings.TrimSpace(value))
+	}
+	return nil
+}
+
+// configStore holds the live Config behind an atomic pointer so the
+// signal handler goroutine can publish a reloaded Config while the main
+// loop keeps reading the previous one without locking.
+type configStore struct {
+	v atomic.Value
+}
+
+func newConfigStore(cfg Config) *configStore {
+	s := &configStore{}
+	s.v.Store(cfg)
+	return s
+}
+
+func (s *configStore) Load() Config {
+	return s.v.Load().(Config)
+}
+
+func (s *configStore) Store(cfg Config) {
+	s.v.Store(cfg)
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+func getEnvInt64(key string, fallback int64) int64 {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	if v := os.Getenv(key); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return fallback
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
+
+// getEnvPositiveDuration behaves like getEnvDuration but also falls back
+// to fallback when the parsed value is zero or negative. Used for
+// durations that get handed straight to time.NewTicker/Ticker.Reset,
+// which panic on a non-positive interval.
+func getEnvPositiveDuration(key string, fallback time.Duration) time.Duration {
+	if d := getEnvDuration(key, fallback); d > 0 {
+		return d
+	}
+	return fallback
+}
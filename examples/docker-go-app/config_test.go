@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadConfigRejectsNonPositiveWriteInterval(t *testing.T) {
+	tests := []string{"0", "0s", "-5s"}
+	for _, v := range tests {
+		t.Setenv("APP_WRITE_INTERVAL", v)
+		cfg := loadConfig()
+		if cfg.WriteInterval <= 0 {
+			t.Errorf("APP_WRITE_INTERVAL=%q: got non-positive WriteInterval %v, want fallback", v, cfg.WriteInterval)
+		}
+	}
+}
+
+func TestLoadConfigAcceptsPositiveWriteInterval(t *testing.T) {
+	t.Setenv("APP_WRITE_INTERVAL", "5s")
+	cfg := loadConfig()
+	if cfg.WriteInterval != 5*time.Second {
+		t.Errorf("got WriteInterval %v, want 5s", cfg.WriteInterval)
+	}
+}
+
+func TestLoadConfigRejectsNonPositiveFlushInterval(t *testing.T) {
+	for _, v := range []string{"0", "0s", "-1s"} {
+		t.Setenv("APP_LOG_FLUSH_INTERVAL", v)
+		cfg := loadConfig()
+		if cfg.FlushInterval <= 0 {
+			t.Errorf("APP_LOG_FLUSH_INTERVAL=%q: got non-positive FlushInterval %v, want fallback", v, cfg.FlushInterval)
+		}
+	}
+}
+
+func TestLoadConfigRejectsNonPositiveHTTPSinkFlushInterval(t *testing.T) {
+	for _, v := range []string{"0", "0s", "-1s"} {
+		t.Setenv("APP_HTTP_SINK_FLUSH_INTERVAL", v)
+		cfg := loadConfig()
+		if cfg.HTTPSinkFlushInterval <= 0 {
+			t.Errorf("APP_HTTP_SINK_FLUSH_INTERVAL=%q: got non-positive HTTPSinkFlushInterval %v, want fallback", v, cfg.HTTPSinkFlushInterval)
+		}
+	}
+}
+
+func TestGetEnvPositiveDurationFallsBackOnNonPositive(t *testing.T) {
+	fallback := 30 * time.Second
+	for _, v := range []string{"0", "-1s"} {
+		t.Setenv("APP_TEST_DURATION", v)
+		if got := getEnvPositiveDuration("APP_TEST_DURATION", fallback); got != fallback {
+			t.Errorf("getEnvPositiveDuration(%q) = %v, want fallback %v", v, got, fallback)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLoggerFiltersBelowLoggerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(textEncoder{}, LevelWarn, Sink{Writer: &buf, Level: LevelDebug})
+
+	logger.Infof("should be dropped")
+	if buf.Len() != 0 {
+		t.Fatalf("expected nothing written below logger level, got %q", buf.String())
+	}
+
+	logger.Errorf("should pass")
+	if buf.Len() == 0 {
+		t.Fatalf("expected ERROR record to pass the WARN floor")
+	}
+}
+
+func TestLoggerFiltersPerSinkLevel(t *testing.T) {
+	var quiet, verbose bytes.Buffer
+	logger := NewLogger(textEncoder{}, LevelDebug,
+		Sink{Writer: &quiet, Level: LevelError},
+		Sink{Writer: &verbose, Level: LevelDebug},
+	)
+
+	logger.Infof("info message")
+
+	if quiet.Len() != 0 {
+		t.Errorf("ERROR-only sink received a record below its level: %q", quiet.String())
+	}
+	if verbose.Len() == 0 {
+		t.Errorf("DEBUG sink should have received the INFO record")
+	}
+}
+
+// fakeLevelWriter records the level it was called with so tests can
+// assert the Logger dispatches through WriteLevel rather than Write for
+// sinks that implement LevelWriter.
+type fakeLevelWriter struct {
+	lastLevel   Level
+	viaWrite    bool
+	viaWriteLvl bool
+}
+
+func (f *fakeLevelWriter) Write(p []byte) (int, error) {
+	f.viaWrite = true
+	return len(p), nil
+}
+
+func (f *fakeLevelWriter) WriteLevel(level Level, p []byte) (int, error) {
+	f.viaWriteLvl = true
+	f.lastLevel = level
+	return len(p), nil
+}
+
+func TestLoggerDispatchesLevelWriterViaWriteLevel(t *testing.T) {
+	fw := &fakeLevelWriter{}
+	logger := NewLogger(textEncoder{}, LevelDebug, Sink{Writer: fw, Level: LevelDebug})
+
+	logger.Errorf("boom")
+
+	if !fw.viaWriteLvl {
+		t.Fatalf("expected Logger to call WriteLevel on a LevelWriter sink")
+	}
+	if fw.viaWrite {
+		t.Fatalf("expected Logger not to fall back to Write for a LevelWriter sink")
+	}
+	if fw.lastLevel != LevelError {
+		t.Fatalf("got level %v, want %v", fw.lastLevel, LevelError)
+	}
+}
@@ -1,72 +1,124 @@
 package main
 
 import (
-	"fmt"
+	"context"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 )
 
-const (
-	logFile     = "/var/log/app.log"
-	maxFileSize = 1024 * 1024 // 1MB
-	interval    = 30 * time.Second
-)
-
 func main() {
-	log.Println("Starting Go application service...")
-	
-	for {
-		// Check file size and rotate if needed
-		if err := rotateLogIfNeeded(); err != nil {
-			log.Printf("Error rotating log: %v", err)
-		}
-		
-		// Write log message
-		if err := writeLogMessage(); err != nil {
-			log.Printf("Error writing log message: %v", err)
-		}
-		
-		// Wait for next interval
-		time.Sleep(interval)
-	}
-}
+	cfg := loadConfig()
+	store := newConfigStore(cfg)
 
-func rotateLogIfNeeded() error {
-	info, err := os.Stat(logFile)
+	lf, err := OpenLogFile(cfg.LogFile, cfg.FlushInterval)
 	if err != nil {
-		// File doesn't exist, which is fine
-		if os.IsNotExist(err) {
-			return nil
-		}
-		return err
+		log.Fatalf("Error opening log file: %v", err)
+	}
+
+	sinks := []Sink{
+		{Writer: lf, Level: LevelDebug},
+		NewStderrSink(parseLevel(cfg.StderrLevel)),
 	}
-	
-	if info.Size() >= maxFileSize {
-		log.Printf("Log file size (%d bytes) exceeds limit, rotating...", info.Size())
-		if err := os.Remove(logFile); err != nil {
-			return err
+
+	if cfg.SyslogEnabled {
+		syslogSink, err := NewSyslogSink(cfg.SyslogFacility, parseLevel(cfg.SyslogLevel))
+		if err != nil {
+			log.Printf("Error configuring syslog sink: %v", err)
+		} else {
+			sinks = append(sinks, syslogSink)
 		}
-		log.Println("Log file rotated")
 	}
-	
-	return nil
-}
 
-func writeLogMessage() error {
-	f, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return err
+	var httpSink *HTTPSink
+	if cfg.HTTPSinkURL != "" {
+		httpSink = NewHTTPSink(cfg.HTTPSinkURL, cfg.HTTPSinkBatchSize, cfg.HTTPSinkFlushInterval, cfg.HTTPSinkMaxRetries)
+		sinks = append(sinks, Sink{Writer: httpSink, Level: parseLevel(cfg.HTTPSinkLevel)})
+	}
+
+	logger := NewLogger(encoderFor(cfg.LogFormat), parseLevel(cfg.LogLevel), sinks...)
+	if httpSink != nil {
+		httpSink.SetErrorReporter(logger.Errorf)
+	}
+	logger.Infof("Starting Go application service...")
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	sigHUP := make(chan os.Signal, 1)
+	signal.Notify(sigHUP, syscall.SIGHUP)
+
+	sigUSR1 := make(chan os.Signal, 1)
+	signal.Notify(sigUSR1, syscall.SIGUSR1)
+
+	sigUSR2 := make(chan os.Signal, 1)
+	signal.Notify(sigUSR2, syscall.SIGUSR2)
+
+	run(ctx, store, lf, logger, sigHUP, sigUSR1, sigUSR2)
+
+	logger.Infof("Shutting down, flushing log file...")
+	if httpSink != nil {
+		httpSink.Close()
 	}
-	defer f.Close()
-	
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	message := fmt.Sprintf("[%s] Hello from GO !!!\n", timestamp)
-	
-	if _, err := f.WriteString(message); err != nil {
-		return err
+	if err := lf.Close(); err != nil {
+		logger.Errorf("Error closing log file: %v", err)
+	}
+}
+
+// run drives the main loop until ctx is cancelled (SIGINT/SIGTERM),
+// rotating and writing on cfg.WriteInterval while handling SIGHUP
+// (reopen the file), SIGUSR1 (force rotation), and SIGUSR2 (reload
+// config) as they arrive.
+func run(ctx context.Context, store *configStore, lf *LogFile, logger *Logger, sigHUP, sigUSR1, sigUSR2 <-chan os.Signal) {
+	state := newRotateState()
+
+	cfg := store.Load()
+	ticker := time.NewTicker(cfg.WriteInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			cfg := store.Load()
+			if err := rotateLogIfNeeded(cfg, state, lf, logger); err != nil {
+				logger.Errorf("Error rotating log: %v", err)
+			}
+			if err := writeLogMessage(logger); err != nil {
+				logger.Errorf("Error writing log message: %v", err)
+			}
+
+		case <-sigHUP:
+			logger.Infof("Received SIGHUP, reopening log file")
+			if err := lf.Reopen(); err != nil {
+				logger.Errorf("Error reopening log file: %v", err)
+			}
+
+		case <-sigUSR1:
+			logger.Infof("Received SIGUSR1, forcing rotation")
+			if err := forceRotate(store.Load(), state, lf, logger); err != nil {
+				logger.Errorf("Error forcing rotation: %v", err)
+			}
+
+		case <-sigUSR2:
+			logger.Infof("Received SIGUSR2, reloading configuration")
+			newCfg, err := reloadConfig()
+			if err != nil {
+				logger.Errorf("Error reloading config: %v", err)
+				continue
+			}
+			store.Store(newCfg)
+			logger.SetLevel(parseLevel(newCfg.LogLevel))
+			ticker.Reset(newCfg.WriteInterval)
+		}
 	}
-	
-	log.Printf("Logged: %s", message[:len(message)-1]) // Remove newline for log output
+}
+
+func writeLogMessage(logger *Logger) error {
+	logger.Infof("Hello from GO !!!")
 	return nil
 }
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// rotateState tracks rotation bookkeeping that must survive across ticks.
+type rotateState struct {
+	lastRotation time.Time
+}
+
+func newRotateState() *rotateState {
+	return &rotateState{lastRotation: time.Now()}
+}
+
+// rotateLogIfNeeded asks lf to rotate whenever the active log has reached
+// cfg.MaxSize or, if cfg.RotateDaily is set, once a day has elapsed
+// since the last rotation. lf.Rotate holds its write lock across the
+// flush, rename, and reopen so no writer ever loses data or writes to a
+// stale descriptor. Backups beyond cfg.MaxBackups or older than
+// cfg.MaxAge are pruned, and cfg.Compress gzips the freshly rotated
+// backup asynchronously so rotation never blocks the writer.
+func rotateLogIfNeeded(cfg Config, state *rotateState, lf *LogFile, logger *Logger) error {
+	info, err := os.Stat(cfg.LogFile)
+	if err != nil {
+		// File doesn't exist, which is fine
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	dueToSize := info.Size() >= cfg.MaxSize
+	dueToAge := cfg.RotateDaily && time.Since(state.lastRotation) >= 24*time.Hour
+	if !dueToSize && !dueToAge {
+		return nil
+	}
+
+	if dueToSize {
+		logger.Infof("Log file size (%d bytes) triggered rotation", info.Size())
+	} else {
+		logger.Infof("Daily rollover triggered rotation")
+	}
+	return doRotate(cfg, state, lf, logger)
+}
+
+// forceRotate rotates the log file immediately regardless of size or
+// age, for operators who want an out-of-band rotation (e.g. via
+// SIGUSR1).
+func forceRotate(cfg Config, state *rotateState, lf *LogFile, logger *Logger) error {
+	logger.Infof("Forcing log rotation")
+	return doRotate(cfg, state, lf, logger)
+}
+
+// doRotate renames the active log to a timestamped backup and opens a
+// fresh file in its place, then compresses and prunes backups per cfg.
+func doRotate(cfg Config, state *rotateState, lf *LogFile, logger *Logger) error {
+	backup := backupName(cfg.LogFile, time.Now())
+	if err := lf.Rotate(backup); err != nil {
+		return fmt.Errorf("rotate: %w", err)
+	}
+	state.lastRotation = time.Now()
+	logger.Infof("Log file rotated to %s", backup)
+
+	if cfg.Compress {
+		go compressBackup(backup, logger)
+	}
+
+	return pruneBackups(cfg, logger)
+}
+
+// backupName returns a timestamped backup path for logFile, appending a
+// monotonic counter if a backup for the same timestamp already exists.
+func backupName(logFile string, ts time.Time) string {
+	ext := filepath.Ext(logFile)
+	base := strings.TrimSuffix(logFile, ext)
+	stamp := ts.Format("2006-01-02T15-04-05")
+
+	candidate := fmt.Sprintf("%s-%s%s", base, stamp, ext)
+	for n := 1; fileExists(candidate); n++ {
+		candidate = fmt.Sprintf("%s-%s.%d%s", base, stamp, n, ext)
+	}
+	return candidate
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// compressBackup gzips a rotated log file and removes the uncompressed
+// copy once the compressed one is safely on disk. It is meant to run in
+// its own goroutine so rotation doesn't wait on the I/O.
+func compressBackup(path string, logger *Logger) {
+	in, err := os.Open(path)
+	if err != nil {
+		logger.Errorf("Error opening %s for compression: %v", path, err)
+		return
+	}
+	defer in.Close()
+
+	out, err := os.Create(path + ".gz")
+	if err != nil {
+		logger.Errorf("Error creating %s.gz: %v", path, err)
+		return
+	}
+	defer out.Close()
+
+	gw := gzip.NewWriter(out)
+	if _, err := io.Copy(gw, in); err != nil {
+		logger.Errorf("Error compressing %s: %v", path, err)
+		gw.Close()
+		return
+	}
+	if err := gw.Close(); err != nil {
+		logger.Errorf("Error closing gzip writer for %s: %v", path, err)
+		return
+	}
+	if err := os.Remove(path); err != nil {
+		logger.Errorf("Error removing uncompressed backup %s: %v", path, err)
+	}
+}
+
+type backupFile struct {
+	path    string
+	modTime time.Time
+}
+
+// pruneBackups deletes rotated backups beyond cfg.MaxBackups and any
+// older than cfg.MaxAge, newest first.
+func pruneBackups(cfg Config, logger *Logger) error {
+	dir := filepath.Dir(cfg.LogFile)
+	ext := filepath.Ext(cfg.LogFile)
+	base := strings.TrimSuffix(filepath.Base(cfg.LogFile), ext)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var backups []backupFile
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), base+"-") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{path: filepath.Join(dir, e.Name()), modTime: info.ModTime()})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].modTime.After(backups[j].modTime) })
+
+	now := time.Now()
+	for i, b := range backups {
+		expired := cfg.MaxAge > 0 && now.Sub(b.modTime) > cfg.MaxAge
+		excess := cfg.MaxBackups > 0 && i >= cfg.MaxBackups
+		if expired || excess {
+			if err := os.Remove(b.path); err != nil {
+				logger.Errorf("Error removing old backup %s: %v", b.path, err)
+			}
+		}
+	}
+
+	return nil
+}
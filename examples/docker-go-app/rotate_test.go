@@ -0,0 +1,104 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testLogger() *Logger {
+	return NewLogger(textEncoder{}, LevelDebug, Sink{Writer: io.Discard, Level: LevelDebug})
+}
+
+func TestRotateLogIfNeededSkipsBelowThreshold(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	if err := os.WriteFile(path, []byte("small"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lf, err := OpenLogFile(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+
+	cfg := Config{LogFile: path, MaxSize: 1024}
+	state := newRotateState()
+
+	if err := rotateLogIfNeeded(cfg, state, lf, testLogger()); err != nil {
+		t.Fatalf("rotateLogIfNeeded: %v", err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected log file to remain in place, got: %v", err)
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	if len(matches) != 0 {
+		t.Fatalf("expected no backups, got %v", matches)
+	}
+}
+
+func TestRotateLogIfNeededRotatesOnSize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	if err := os.WriteFile(path, []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lf, err := OpenLogFile(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+
+	cfg := Config{LogFile: path, MaxSize: 5, MaxBackups: 10}
+	state := newRotateState()
+
+	if err := rotateLogIfNeeded(cfg, state, lf, testLogger()); err != nil {
+		t.Fatalf("rotateLogIfNeeded: %v", err)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	if len(matches) != 1 {
+		t.Fatalf("expected exactly one backup, got %v", matches)
+	}
+	data, err := os.ReadFile(matches[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "0123456789" {
+		t.Fatalf("backup contents = %q, want original content preserved", data)
+	}
+}
+
+func TestPruneBackupsRespectsMaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		name := backupName(path, now.Add(time.Duration(i)*time.Second))
+		if err := os.WriteFile(name, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		// Ensure distinct, increasing mtimes regardless of filesystem
+		// timestamp resolution.
+		mtime := now.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(name, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := Config{LogFile: path, MaxBackups: 2}
+	if err := pruneBackups(cfg, testLogger()); err != nil {
+		t.Fatalf("pruneBackups: %v", err)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 backups to survive pruning, got %d: %v", len(matches), matches)
+	}
+}
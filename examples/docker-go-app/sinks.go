@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"log/syslog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewStderrSink sends records to stderr, for container runtimes that
+// collect stdout/stderr directly.
+func NewStderrSink(level Level) Sink {
+	return Sink{Writer: os.Stderr, Level: level}
+}
+
+// NewSyslogSink sends records to the local syslog daemon under the given
+// facility (e.g. "LOCAL0", "USER"), tagging each message with the
+// syslog severity matching its own Level rather than one fixed priority
+// for everything that passes through the sink.
+func NewSyslogSink(facility string, level Level) (Sink, error) {
+	f, err := parseSyslogFacility(facility)
+	if err != nil {
+		return Sink{}, err
+	}
+	w, err := syslog.New(f|syslog.LOG_INFO, "docker-go-app")
+	if err != nil {
+		return Sink{}, fmt.Errorf("syslog sink: %w", err)
+	}
+	return Sink{Writer: &syslogWriter{w: w}, Level: level}, nil
+}
+
+// syslogWriter adapts a *syslog.Writer, whose severity is otherwise
+// fixed at construction, to LevelWriter so the Logger can tag each
+// message with the syslog severity matching its own Level.
+type syslogWriter struct {
+	w *syslog.Writer
+}
+
+// Write satisfies io.Writer for callers that don't go through
+// WriteLevel, logging at INFO severity.
+func (sw *syslogWriter) Write(p []byte) (int, error) {
+	return sw.WriteLevel(LevelInfo, p)
+}
+
+func (sw *syslogWriter) WriteLevel(level Level, p []byte) (int, error) {
+	msg := string(p)
+
+	var err error
+	switch level {
+	case LevelDebug:
+		err = sw.w.Debug(msg)
+	case LevelInfo:
+		err = sw.w.Info(msg)
+	case LevelWarn:
+		err = sw.w.Warning(msg)
+	case LevelError:
+		err = sw.w.Err(msg)
+	default:
+		err = sw.w.Info(msg)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func parseSyslogFacility(name string) (syslog.Priority, error) {
+	switch strings.ToUpper(name) {
+	case "USER", "":
+		return syslog.LOG_USER, nil
+	case "DAEMON":
+		return syslog.LOG_DAEMON, nil
+	case "LOCAL0":
+		return syslog.LOG_LOCAL0, nil
+	case "LOCAL1":
+		return syslog.LOG_LOCAL1, nil
+	case "LOCAL2":
+		return syslog.LOG_LOCAL2, nil
+	case "LOCAL3":
+		return syslog.LOG_LOCAL3, nil
+	case "LOCAL4":
+		return syslog.LOG_LOCAL4, nil
+	case "LOCAL5":
+		return syslog.LOG_LOCAL5, nil
+	case "LOCAL6":
+		return syslog.LOG_LOCAL6, nil
+	case "LOCAL7":
+		return syslog.LOG_LOCAL7, nil
+	default:
+		return 0, fmt.Errorf("unknown syslog facility %q", name)
+	}
+}
+
+// HTTPSink batches encoded records and POSTs them to a remote collector,
+// retrying failed batches with exponential backoff. Write only buffers;
+// batches go out on a timer or once batchSize is reached.
+type HTTPSink struct {
+	mu            sync.Mutex
+	url           string
+	client        *http.Client
+	batch         [][]byte
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	errorf        func(format string, args ...interface{})
+	flushNow      chan struct{}
+	stop          chan struct{}
+	done          chan struct{}
+}
+
+// NewHTTPSink starts a background goroutine that flushes batches of
+// encoded records to url every flushInterval, or sooner once batchSize
+// records have queued up. Failures are reported via the standard
+// library's log package until SetErrorReporter is called; main wires it
+// up to the application's own Logger once one exists, since the Logger
+// is constructed after its sinks (including this one).
+func NewHTTPSink(url string, batchSize int, flushInterval time.Duration, maxRetries int) *HTTPSink {
+	s := &HTTPSink{
+		url:           url,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		maxRetries:    maxRetries,
+		errorf:        log.Printf,
+		flushNow:      make(chan struct{}, 1),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go s.flushLoop()
+	return s
+}
+
+// SetErrorReporter routes flush failures through fn instead of the
+// standard library's log package, so a sink that's giving up on a batch
+// shows up through the same Encoder/sink pipeline as every other log
+// message rather than bypassing it. Safe to call concurrently with
+// flush.
+func (s *HTTPSink) SetErrorReporter(fn func(format string, args ...interface{})) {
+	s.mu.Lock()
+	s.errorf = fn
+	s.mu.Unlock()
+}
+
+// Write only buffers p; the POST (and its retry/backoff) always happens
+// on the flushLoop goroutine so a slow or unreachable collector can
+// never block the caller, such as a Logger call made from the main
+// select loop.
+func (s *HTTPSink) Write(p []byte) (int, error) {
+	cp := append([]byte(nil), p...)
+
+	s.mu.Lock()
+	s.batch = append(s.batch, cp)
+	full := len(s.batch) >= s.batchSize
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.flushNow <- struct{}{}:
+		default:
+			// a flush is already pending; flushLoop will pick up
+			// everything queued so far once it runs.
+		}
+	}
+	return len(p), nil
+}
+
+func (s *HTTPSink) flushLoop() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-s.flushNow:
+			s.flush()
+		case <-s.stop:
+			s.flush()
+			return
+		}
+	}
+}
+
+// flush POSTs the queued batch, retrying with exponential backoff up to
+// maxRetries before giving up and dropping it.
+func (s *HTTPSink) flush() {
+	s.mu.Lock()
+	if len(s.batch) == 0 {
+		s.mu.Unlock()
+		return
+	}
+	batch := s.batch
+	s.batch = nil
+	errorf := s.errorf
+	s.mu.Unlock()
+
+	body := bytes.Join(batch, nil)
+	backoff := 500 * time.Millisecond
+	for attempt := 0; attempt <= s.maxRetries; attempt++ {
+		if err := s.post(body); err != nil {
+			if attempt == s.maxRetries {
+				errorf("http sink: giving up after %d attempts: %v", attempt+1, err)
+				return
+			}
+			time.Sleep(backoff)
+			backoff *= 2
+			continue
+		}
+		return
+	}
+}
+
+func (s *HTTPSink) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close stops the flush goroutine after a final flush of any queued
+// batch.
+func (s *HTTPSink) Close() error {
+	close(s.stop)
+	<-s.done
+	return nil
+}
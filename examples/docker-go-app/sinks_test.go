@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPSinkWriteDoesNotBlockOnSlowCollector(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Second)
+	}))
+	defer srv.Close()
+
+	sink := NewHTTPSink(srv.URL, 1, time.Hour, 0)
+	defer sink.Close()
+
+	start := time.Now()
+	if _, err := sink.Write([]byte(`{"message":"test"}` + "\n")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("Write blocked for %v batching a full batch, want it to return immediately", elapsed)
+	}
+}
+
+func TestHTTPSinkReportsGivingUpThroughErrorReporter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	sink := NewHTTPSink(srv.URL, 1, time.Hour, 0)
+	defer sink.Close()
+
+	reported := make(chan string, 1)
+	sink.SetErrorReporter(func(format string, args ...interface{}) {
+		reported <- fmt.Sprintf(format, args...)
+	})
+
+	if _, err := sink.Write([]byte(`{"message":"test"}` + "\n")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	select {
+	case msg := <-reported:
+		if !strings.Contains(msg, "giving up") {
+			t.Errorf("error reporter got %q, want a message about giving up after retries", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("error reporter was never called")
+	}
+}
+
+func TestLoggerLogDoesNotBlockOnSlowSink(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Second)
+	}))
+	defer srv.Close()
+
+	sink := NewHTTPSink(srv.URL, 1, time.Hour, 0)
+	defer sink.Close()
+
+	logger := NewLogger(jsonEncoder{}, LevelDebug, Sink{Writer: sink, Level: LevelDebug})
+
+	start := time.Now()
+	logger.Infof("hello")
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("Infof blocked for %v, want the slow sink to be handed off asynchronously", elapsed)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogFile is a concurrency-safe, buffered writer over a single file on
+// disk. Writes land in an in-memory buffer and a background goroutine
+// flushes it on a fixed interval, so many goroutines can log without
+// each one forcing a syscall. The same mutex that guards writes is held
+// across Rotate, so a rotation can never interleave with a Write or hand
+// a writer a stale, already-renamed descriptor.
+type LogFile struct {
+	mu            sync.Mutex
+	path          string
+	file          *os.File
+	buf           *bufio.Writer
+	flushInterval time.Duration
+	stop          chan struct{}
+	done          chan struct{}
+}
+
+// OpenLogFile opens (creating if necessary) the file at path and starts
+// a background goroutine that flushes the buffer every flushInterval.
+func OpenLogFile(path string, flushInterval time.Duration) (*LogFile, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	lf := &LogFile{
+		path:          path,
+		file:          f,
+		buf:           bufio.NewWriter(f),
+		flushInterval: flushInterval,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go lf.flushLoop()
+	return lf, nil
+}
+
+func (lf *LogFile) flushLoop() {
+	defer close(lf.done)
+	ticker := time.NewTicker(lf.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			lf.mu.Lock()
+			lf.buf.Flush()
+			lf.mu.Unlock()
+		case <-lf.stop:
+			return
+		}
+	}
+}
+
+// Write buffers p, to be flushed on the next tick or explicit Sync.
+func (lf *LogFile) Write(p []byte) (int, error) {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	return lf.buf.Write(p)
+}
+
+// Sync flushes any buffered data and fsyncs the underlying file.
+func (lf *LogFile) Sync() error {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	if err := lf.buf.Flush(); err != nil {
+		return err
+	}
+	return lf.file.Sync()
+}
+
+// Close stops the flush goroutine and closes the underlying file after
+// a final flush.
+func (lf *LogFile) Close() error {
+	close(lf.stop)
+	<-lf.done
+
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	if err := lf.buf.Flush(); err != nil {
+		lf.file.Close()
+		return err
+	}
+	return lf.file.Close()
+}
+
+// Rotate renames the current file to backup and opens a fresh file at
+// the original path. Everything happens under the write lock so
+// concurrent writers neither lose buffered data nor write to a stale
+// descriptor. The old file is kept open until the replacement is
+// confirmed open, so a failed rename or reopen leaves lf still writing
+// to a valid descriptor instead of a closed one.
+func (lf *LogFile) Rotate(backup string) error {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+
+	if err := lf.buf.Flush(); err != nil {
+		return err
+	}
+	if err := os.Rename(lf.path, backup); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(lf.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		// The rename already went through, so lf.file now refers to
+		// backup's inode under the hood. Keep writing to it rather than
+		// closing it on a half-finished rotation. A later Reopen swaps in
+		// a fresh file at path; a later Rotate does not, since nothing is
+		// left at path to rename.
+		return err
+	}
+
+	old := lf.file
+	lf.file = f
+	lf.buf = bufio.NewWriter(f)
+	return old.Close()
+}
+
+// Reopen reopens the file at path, picking up whatever inode now lives
+// there. Used when an external tool such as logrotate has moved the
+// file out from under us (typically on SIGHUP). The old file is kept
+// open until the replacement is confirmed open, so a failed reopen
+// leaves lf still writing to a valid descriptor instead of a closed one.
+func (lf *LogFile) Reopen() error {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+
+	if err := lf.buf.Flush(); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(lf.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	old := lf.file
+	lf.file = f
+	lf.buf = bufio.NewWriter(f)
+	return old.Close()
+}